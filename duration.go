@@ -0,0 +1,244 @@
+package iso8601
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// averageDaysPerYear is the mean Gregorian calendar year length, used by
+// Duration.Approximate to collapse the calendar components (years, months)
+// into a single time.Duration.
+const averageDaysPerYear = 365.2425
+
+// iso8601DurationDesignated matches the PnYnMnWnDTnHnMnS form, e.g.
+// "P3Y6M4DT12H30M5S" or the week form "P4W". Every component is optional,
+// but at least one must be present.
+var iso8601DurationDesignated = regexp.MustCompile(`^P` +
+	`(?:([0-9]+)Y)?(?:([0-9]+)M)?(?:([0-9]+)W)?(?:([0-9]+)D)?` +
+	`(?:T(?:([0-9]+(?:[.,][0-9]+)?)H)?(?:([0-9]+(?:[.,][0-9]+)?)M)?(?:([0-9]+(?:[.,][0-9]+)?)S)?)?$`)
+
+// iso8601DurationDateTime matches the alternate "date-time" duration form,
+// e.g. "P0003-06-04T12:30:05".
+var iso8601DurationDateTime = regexp.MustCompile(
+	`^P([0-9]{4})-([0-9]{2})-([0-9]{2})T([0-9]{2}):([0-9]{2}):([0-9]{2}(?:[.,][0-9]+)?)$`)
+
+// Duration represents an ISO 8601 duration: a calendar-based component
+// (years, months, weeks, days) plus a fixed-length time-of-day component.
+// Years and months are not stored as a fixed number of nanoseconds because
+// their length in absolute time depends on the date they are applied to;
+// use AddTo to apply a Duration to a specific instant, or Approximate to
+// collapse it to a single time.Duration using an average calendar year.
+type Duration struct {
+	Years    int
+	Months   int
+	Weeks    int
+	Days     int
+	TimePart time.Duration
+}
+
+func durationParseError(s string) error {
+	return &time.ParseError{
+		Layout:     "ISO 8601 Duration",
+		Value:      s,
+		LayoutElem: "",
+		ValueElem:  "",
+		Message:    ": duration is not in ISO 8601 format",
+	}
+}
+
+func parseISO8601DurationFloat(s string) float64 {
+	value, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to convert %#v to float64", s))
+	}
+
+	return value
+}
+
+// ParseISO8601Duration parses an ISO 8601 duration, either in the designated
+// form "PnYnMnWnDTnHnMnS" (e.g. "P3Y6M4DT12H30M5S", "P4W") or the alternate
+// date-time form "P<year>-<month>-<day>T<hour>:<minute>:<second>" (e.g.
+// "P0003-06-04T12:30:05"). A fractional value is accepted only on the
+// hours, minutes or seconds component, and only on the smallest of those
+// present, e.g. "PT0.5H"; the year, month, week and day components must be
+// whole numbers.
+func ParseISO8601Duration(s string) (Duration, error) {
+	if parts := iso8601DurationDateTime.FindStringSubmatch(s); parts != nil {
+		return Duration{
+			Years:  atoip(parts[1]),
+			Months: atoip(parts[2]),
+			Days:   atoip(parts[3]),
+			TimePart: time.Duration(atoip(parts[4]))*time.Hour +
+				time.Duration(atoip(parts[5]))*time.Minute +
+				time.Duration(parseISO8601DurationFloat(parts[6])*float64(time.Second)),
+		}, nil
+	}
+
+	parts := iso8601DurationDesignated.FindStringSubmatch(s)
+	if parts == nil {
+		return Duration{}, durationParseError(s)
+	}
+
+	present := false
+	for _, part := range parts[1:] {
+		if part != "" {
+			present = true
+			break
+		}
+	}
+	if !present {
+		return Duration{}, durationParseError(s)
+	}
+
+	// A fraction is only valid on the smallest-magnitude of H/M/S that's
+	// actually present: reject e.g. "PT1.5H30M", where the fraction isn't
+	// on the last component.
+	hasFraction := func(part string) bool {
+		return strings.ContainsAny(part, ".,")
+	}
+	if (parts[5] != "" && hasFraction(parts[5]) && (parts[6] != "" || parts[7] != "")) ||
+		(parts[6] != "" && hasFraction(parts[6]) && parts[7] != "") {
+		return Duration{}, durationParseError(s)
+	}
+
+	var d Duration
+
+	if parts[1] != "" {
+		d.Years = atoip(parts[1])
+	}
+	if parts[2] != "" {
+		d.Months = atoip(parts[2])
+	}
+	if parts[3] != "" {
+		d.Weeks = atoip(parts[3])
+	}
+	if parts[4] != "" {
+		d.Days = atoip(parts[4])
+	}
+
+	var hours, minutes, seconds float64
+	if parts[5] != "" {
+		hours = parseISO8601DurationFloat(parts[5])
+	}
+	if parts[6] != "" {
+		minutes = parseISO8601DurationFloat(parts[6])
+	}
+	if parts[7] != "" {
+		seconds = parseISO8601DurationFloat(parts[7])
+	}
+
+	d.TimePart = time.Duration(hours*float64(time.Hour) + minutes*float64(time.Minute) + seconds*float64(time.Second))
+
+	return d, nil
+}
+
+// String returns the canonical ISO 8601 representation of d, e.g.
+// "P3Y6M4DT12H30M5S". A zero-valued Duration is rendered as "PT0S".
+func (d Duration) String() string {
+	if d.Years == 0 && d.Months == 0 && d.Weeks == 0 && d.Days == 0 && d.TimePart == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteByte('P')
+
+	if d.Years != 0 {
+		fmt.Fprintf(&b, "%dY", d.Years)
+	}
+	if d.Months != 0 {
+		fmt.Fprintf(&b, "%dM", d.Months)
+	}
+	if d.Weeks != 0 {
+		fmt.Fprintf(&b, "%dW", d.Weeks)
+	}
+	if d.Days != 0 {
+		fmt.Fprintf(&b, "%dD", d.Days)
+	}
+
+	if d.TimePart != 0 {
+		b.WriteByte('T')
+
+		hours := d.TimePart / time.Hour
+		remainder := d.TimePart % time.Hour
+		minutes := remainder / time.Minute
+		remainder = remainder % time.Minute
+		seconds := float64(remainder) / float64(time.Second)
+
+		if hours != 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes != 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds != 0 {
+			fmt.Fprintf(&b, "%sS", strconv.FormatFloat(seconds, 'f', -1, 64))
+		}
+	}
+
+	return b.String()
+}
+
+// AddTo returns the time t with d added to it. The calendar components
+// (years, months, weeks, days) are applied with time.Time.AddDate, and the
+// time-of-day component is applied afterwards with time.Time.Add.
+func (d Duration) AddTo(t time.Time) time.Time {
+	return t.AddDate(d.Years, d.Months, d.Weeks*7+d.Days).Add(d.TimePart)
+}
+
+// Approximate collapses d to a single time.Duration, using
+// averageDaysPerYear to approximate the variable-length calendar
+// components. Callers that need an exact result relative to a specific
+// instant should use AddTo instead.
+func (d Duration) Approximate() time.Duration {
+	days := float64(d.Years)*averageDaysPerYear + float64(d.Months)*(averageDaysPerYear/12) + float64(d.Weeks)*7 + float64(d.Days)
+	return time.Duration(days*24*float64(time.Hour)) + d.TimePart
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The duration is expected to be a quoted string in ISO 8601 format.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	// Ignore null, like in the main JSON package.
+	if string(data) == "null" {
+		return nil
+	}
+
+	// Make sure the string is quoted properly.
+	s := string(data)
+	if len(s) < 2 || (!(s[0] == '"' && s[len(s)-1] == '"') && !(s[0] == '\'' && s[len(s)-1] == '\'')) {
+		return &time.ParseError{
+			Layout:     "ISO 8601 Duration",
+			Value:      s,
+			LayoutElem: "",
+			ValueElem:  "",
+			Message:    ": duration must be a JSON string literal",
+		}
+	}
+
+	// Remove the quotation marks.
+	s = s[1 : len(s)-1]
+
+	var err error
+	*d, err = ParseISO8601Duration(s)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The duration is expected to be in ISO 8601 format.
+func (d *Duration) UnmarshalText(data []byte) error {
+	var err error
+	*d, err = ParseISO8601Duration(string(data))
+	return err
+}
@@ -0,0 +1,84 @@
+package iso8601
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestValidDurations(t *testing.T) {
+	for _, tc := range []struct {
+		s string
+		d Duration
+	}{
+		{"P3Y6M4DT12H30M5S", Duration{Years: 3, Months: 6, Days: 4, TimePart: 12*time.Hour + 30*time.Minute + 5*time.Second}},
+		{"P4W", Duration{Weeks: 4}},
+		{"PT0.5H", Duration{TimePart: 30 * time.Minute}},
+		{"PT0,5H", Duration{TimePart: 30 * time.Minute}},
+		{"P0003-06-04T12:30:05", Duration{Years: 3, Months: 6, Days: 4, TimePart: 12*time.Hour + 30*time.Minute + 5*time.Second}},
+		{"PT0S", Duration{}},
+	} {
+		if d, err := ParseISO8601Duration(tc.s); err != nil {
+			t.Errorf("Failed to parse duration: %#v %#v\n", tc.s, err)
+		} else if d != tc.d {
+			t.Errorf("Incorrect duration value for %#v: expected %#v, got %#v", tc.s, tc.d, d)
+		}
+	}
+}
+
+func TestInvalidDurations(t *testing.T) {
+	for _, s := range []string{"P", "PT", "1Y", "P1S",
+		"PT1.5H30M45S", "PT1.5H45S", "PT1H30.5M45S"} {
+		if _, err := ParseISO8601Duration(s); err == nil {
+			t.Errorf("Expected an error on duration: %#v\n", s)
+		}
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	for _, tc := range []struct {
+		d Duration
+		s string
+	}{
+		{Duration{Years: 3, Months: 6, Days: 4, TimePart: 12*time.Hour + 30*time.Minute + 5*time.Second}, "P3Y6M4DT12H30M5S"},
+		{Duration{Weeks: 4}, "P4W"},
+		{Duration{TimePart: 30 * time.Minute}, "PT30M"},
+		{Duration{}, "PT0S"},
+	} {
+		if s := tc.d.String(); s != tc.s {
+			t.Errorf("Incorrect string for %#v: expected %#v, got %#v", tc.d, tc.s, s)
+		}
+	}
+}
+
+func TestDurationAddTo(t *testing.T) {
+	d := Duration{Years: 1, Months: 2, Days: 3, TimePart: 4 * time.Hour}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := d.AddTo(start)
+	want := time.Date(2021, 3, 4, 4, 0, 0, 0, time.UTC)
+
+	if !end.Equal(want) {
+		t.Errorf("Incorrect AddTo result: expected %v, got %v", want, end)
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	var jsonStruct struct {
+		Duration Duration `json:"duration"`
+	}
+
+	jsonText := `{"duration": "P3Y6M4DT12H30M5S"}`
+	if err := json.Unmarshal([]byte(jsonText), &jsonStruct); err != nil {
+		t.Errorf("Failed to unmarshal JSON: %s: %#v\n", jsonText, err)
+	}
+
+	out, err := json.Marshal(&jsonStruct)
+	if err != nil {
+		t.Errorf("Failed to marshal JSON: %#v\n", err)
+	}
+
+	want := `{"duration":"P3Y6M4DT12H30M5S"}`
+	if string(out) != want {
+		t.Errorf("Incorrect marshaled JSON: expected %#v, got %#v", want, string(out))
+	}
+}
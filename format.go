@@ -0,0 +1,226 @@
+package iso8601
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DateStyle selects which portion of a timestamp Time.Format emits.
+type DateStyle int
+
+const (
+	// DateTimeStyle emits both the calendar date and the time-of-day,
+	// separated by 'T'.
+	DateTimeStyle DateStyle = iota
+	// DateOnlyStyle emits only the calendar date.
+	DateOnlyStyle
+	// TimeOnlyStyle emits only the time-of-day.
+	TimeOnlyStyle
+)
+
+// FormatOptions controls how Time.Format renders a timestamp.
+type FormatOptions struct {
+	// Extended selects the extended form (field separators, e.g.
+	// "2006-01-02T15:04:05") over the basic form ("20060102T150405").
+	Extended bool
+
+	// Style selects whether to emit the date, the time-of-day, or both.
+	Style DateStyle
+
+	// FracPrecision is the number of fractional-second digits to emit:
+	// 0 for none, 1-9 for a fixed number of digits, or -1 to emit as many
+	// digits as needed to represent the value exactly, with trailing
+	// zeros trimmed (and the fractional part omitted entirely if it would
+	// be all zeros).
+	FracPrecision int
+
+	// DecimalComma uses ',' instead of '.' to introduce the
+	// fractional-second digits.
+	DecimalComma bool
+
+	// ZForUTC emits "Z" for a zero UTC offset instead of a numeric
+	// "+00:00"/"+0000" offset.
+	ZForUTC bool
+
+	// OffsetColon separates the hours and minutes of a numeric time zone
+	// offset with ':', e.g. "+09:30" instead of "+0930".
+	OffsetColon bool
+}
+
+// ISO8601Extended renders a timestamp in extended form with a "Z" suffix
+// for UTC and a colon-separated offset otherwise, trimming fractional
+// seconds to no more digits than the value needs, e.g.
+// "2020-02-17T11:39:27.658731Z".
+var ISO8601Extended = FormatOptions{
+	Extended:      true,
+	Style:         DateTimeStyle,
+	FracPrecision: -1,
+	ZForUTC:       true,
+	OffsetColon:   true,
+}
+
+// ISO8601Basic renders a timestamp in basic form with a "Z" suffix for
+// UTC and an unseparated offset otherwise, e.g. "20200217T113927.658731Z".
+var ISO8601Basic = FormatOptions{
+	Extended:      false,
+	Style:         DateTimeStyle,
+	FracPrecision: -1,
+	ZForUTC:       true,
+	OffsetColon:   false,
+}
+
+// ISO8601ExtendedNoColonOffset renders a timestamp in extended form with
+// an unseparated numeric offset in all cases, including for UTC (e.g.
+// "2020-02-17T11:39:27+0000" rather than "...Z"), matching the format
+// some AWS services emit.
+var ISO8601ExtendedNoColonOffset = FormatOptions{
+	Extended:      true,
+	Style:         DateTimeStyle,
+	FracPrecision: -1,
+	ZForUTC:       false,
+	OffsetColon:   false,
+}
+
+// formatYear renders year the way scanDate expects to read it back: plain
+// 4-digit form for the ordinary [0, 9999] range, or a signed expanded form
+// (see MinExpandedYearDigits) otherwise. In extended form, a '-' or 'W'
+// always delimits the year, so any width of at least MinExpandedYearDigits
+// round-trips; it's padded up to that minimum. In basic form nothing
+// delimits the year, so scanDate always consumes exactly
+// MinExpandedYearDigits digits as the year -- formatYear panics rather
+// than silently emit a string ParseISO8601Timestamp can't parse back if
+// year doesn't fit in exactly that width.
+func formatYear(year int, extended bool) string {
+	if year >= 0 && year <= 9999 {
+		return fmt.Sprintf("%04d", year)
+	}
+
+	sign := byte('+')
+	abs := year
+	if year < 0 {
+		sign = '-'
+		abs = -year
+	}
+
+	width := 4
+	if MinExpandedYearDigits > width {
+		width = MinExpandedYearDigits
+	}
+
+	digits := strconv.Itoa(abs)
+	if len(digits) > width {
+		if !extended {
+			panic(fmt.Sprintf("iso8601: year %d cannot be represented in basic form with MinExpandedYearDigits=%d", year, MinExpandedYearDigits))
+		}
+		width = len(digits)
+	}
+	for len(digits) < width {
+		digits = "0" + digits
+	}
+
+	return string(sign) + digits
+}
+
+// formatFraction renders nsec (0-999999999) as fractional-second digits
+// per precision: "" for precision == 0, exactly precision digits for
+// precision > 0, or as many digits as needed (trimmed of trailing zeros)
+// for precision < 0.
+func formatFraction(nsec, precision int) string {
+	if precision == 0 {
+		return ""
+	}
+
+	digits := fmt.Sprintf("%09d", nsec)
+
+	if precision < 0 {
+		return strings.TrimRight(digits, "0")
+	}
+
+	if precision > 9 {
+		precision = 9
+	}
+
+	return digits[:precision]
+}
+
+// Format renders t according to opts. See FormatOptions for the available
+// knobs, or use one of the named presets such as ISO8601Extended.
+func (t Time) Format(opts FormatOptions) string {
+	var b strings.Builder
+
+	if opts.Style != TimeOnlyStyle {
+		year, month, day := t.Date()
+		yearStr := formatYear(year, opts.Extended)
+
+		if opts.Extended {
+			fmt.Fprintf(&b, "%s-%02d-%02d", yearStr, int(month), day)
+		} else {
+			fmt.Fprintf(&b, "%s%02d%02d", yearStr, int(month), day)
+		}
+	}
+
+	if opts.Style == DateOnlyStyle {
+		return b.String()
+	}
+
+	if opts.Style == DateTimeStyle {
+		b.WriteByte('T')
+	}
+
+	hour, minute, second := t.Clock()
+
+	if opts.Extended {
+		fmt.Fprintf(&b, "%02d:%02d:%02d", hour, minute, second)
+	} else {
+		fmt.Fprintf(&b, "%02d%02d%02d", hour, minute, second)
+	}
+
+	if frac := formatFraction(t.Nanosecond(), opts.FracPrecision); frac != "" {
+		if opts.DecimalComma {
+			b.WriteByte(',')
+		} else {
+			b.WriteByte('.')
+		}
+		b.WriteString(frac)
+	}
+
+	_, offsetSeconds := t.Zone()
+
+	if offsetSeconds == 0 && opts.ZForUTC {
+		b.WriteByte('Z')
+		return b.String()
+	}
+
+	sign := byte('+')
+	if offsetSeconds < 0 {
+		sign = '-'
+		offsetSeconds = -offsetSeconds
+	}
+
+	offsetHour := offsetSeconds / 3600
+	offsetMinute := (offsetSeconds % 3600) / 60
+
+	b.WriteByte(sign)
+	if opts.OffsetColon {
+		fmt.Fprintf(&b, "%02d:%02d", offsetHour, offsetMinute)
+	} else {
+		fmt.Fprintf(&b, "%02d%02d", offsetHour, offsetMinute)
+	}
+
+	return b.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface. The time is
+// rendered in extended ISO 8601 form with nanosecond precision trimmed of
+// trailing zeros, so it round-trips faithfully through UnmarshalJSON.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.Format(ISO8601Extended) + `"`), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The time is
+// rendered in extended ISO 8601 form with nanosecond precision trimmed of
+// trailing zeros, so it round-trips faithfully through UnmarshalText.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.Format(ISO8601Extended)), nil
+}
@@ -0,0 +1,143 @@
+package iso8601
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFormatPresets(t *testing.T) {
+	tm := Time{time.Date(2020, 2, 17, 11, 39, 27, 658731000, time.UTC)}
+
+	for _, tc := range []struct {
+		opts FormatOptions
+		want string
+	}{
+		{ISO8601Extended, "2020-02-17T11:39:27.658731Z"},
+		{ISO8601Basic, "20200217T113927.658731Z"},
+		{ISO8601ExtendedNoColonOffset, "2020-02-17T11:39:27.658731+0000"},
+	} {
+		if got := tm.Format(tc.opts); got != tc.want {
+			t.Errorf("Incorrect format for %#v: expected %#v, got %#v", tc.opts, tc.want, got)
+		}
+	}
+}
+
+func TestFormatTrimsTrailingZeros(t *testing.T) {
+	tm := Time{time.Date(2020, 2, 17, 11, 39, 27, 0, time.UTC)}
+
+	if got, want := tm.Format(ISO8601Extended), "2020-02-17T11:39:27Z"; got != want {
+		t.Errorf("Incorrect format: expected %#v, got %#v", want, got)
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	zone := time.FixedZone("-0230", -(2*3600 + 30*60))
+	tm := Time{time.Date(2020, 2, 17, 11, 39, 27, 0, zone)}
+
+	if got, want := tm.Format(ISO8601Extended), "2020-02-17T11:39:27-02:30"; got != want {
+		t.Errorf("Incorrect format: expected %#v, got %#v", want, got)
+	}
+
+	if got, want := tm.Format(ISO8601ExtendedNoColonOffset), "2020-02-17T11:39:27-0230"; got != want {
+		t.Errorf("Incorrect format: expected %#v, got %#v", want, got)
+	}
+}
+
+func TestFormatDateAndTimeOnly(t *testing.T) {
+	tm := Time{time.Date(2020, 2, 17, 11, 39, 27, 0, time.UTC)}
+
+	dateOnly := FormatOptions{Extended: true, Style: DateOnlyStyle}
+	if got, want := tm.Format(dateOnly), "2020-02-17"; got != want {
+		t.Errorf("Incorrect date-only format: expected %#v, got %#v", want, got)
+	}
+
+	timeOnly := FormatOptions{Extended: true, Style: TimeOnlyStyle, ZForUTC: true}
+	if got, want := tm.Format(timeOnly), "11:39:27Z"; got != want {
+		t.Errorf("Incorrect time-only format: expected %#v, got %#v", want, got)
+	}
+}
+
+func TestFormatFixedPrecision(t *testing.T) {
+	tm := Time{time.Date(2020, 2, 17, 11, 39, 27, 600000000, time.UTC)}
+
+	opts := ISO8601Extended
+	opts.FracPrecision = 3
+	if got, want := tm.Format(opts), "2020-02-17T11:39:27.600Z"; got != want {
+		t.Errorf("Incorrect format: expected %#v, got %#v", want, got)
+	}
+}
+
+func TestFormatExpandedYear(t *testing.T) {
+	for _, tc := range []struct {
+		tm   Time
+		want string
+	}{
+		{Time{time.Date(-1, 1, 1, 0, 0, 0, 0, time.UTC)}, "-0001-01-01T00:00:00Z"},
+		{Time{time.Date(10000, 6, 4, 0, 0, 0, 0, time.UTC)}, "+10000-06-04T00:00:00Z"},
+	} {
+		got := tc.tm.Format(ISO8601Extended)
+		if got != tc.want {
+			t.Errorf("Incorrect format for %v: expected %#v, got %#v", tc.tm, tc.want, got)
+		}
+
+		parsed, err := ParseISO8601Timestamp(got)
+		if err != nil {
+			t.Errorf("Failed to round-trip %#v: %#v\n", got, err)
+		} else if !parsed.Equal(tc.tm.Time) {
+			t.Errorf("Round-tripped %#v to %v, expected %v", got, parsed, tc.tm)
+		}
+	}
+}
+
+func TestFormatBasicExpandedYear(t *testing.T) {
+	// A negative year that fits in MinExpandedYearDigits (the default 4)
+	// round-trips fine in basic form, since scanDate's basic-form path
+	// consumes exactly that many digits as the year.
+	tm := Time{time.Date(-1, 1, 1, 0, 0, 0, 0, time.UTC)}
+	got := tm.Format(ISO8601Basic)
+	want := "-0001" + "0101" + "T000000Z"
+	if got != want {
+		t.Errorf("Incorrect basic format: expected %#v, got %#v", want, got)
+	}
+
+	parsed, err := ParseISO8601Timestamp(got)
+	if err != nil {
+		t.Errorf("Failed to round-trip %#v: %#v\n", got, err)
+	} else if !parsed.Equal(tm.Time) {
+		t.Errorf("Round-tripped %#v to %v, expected %v", got, parsed, tm)
+	}
+
+	// A year wider than MinExpandedYearDigits has no unambiguous basic-form
+	// encoding -- scanDate would misread the extra digit as part of the
+	// month/day -- so Format must refuse to emit it rather than producing
+	// a string that silently fails to parse back.
+	wide := Time{time.Date(10000, 6, 4, 0, 0, 0, 0, time.UTC)}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected Format to panic on an un-round-trippable basic-form year\n")
+		}
+	}()
+	wide.Format(ISO8601Basic)
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	var jsonStruct struct {
+		Time Time `json:"time"`
+	}
+
+	jsonText := `{"time": "2020-02-17T11:39:27.658731Z"}`
+	if err := json.Unmarshal([]byte(jsonText), &jsonStruct); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %#v\n", err)
+	}
+
+	out, err := json.Marshal(&jsonStruct)
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %#v\n", err)
+	}
+
+	want := `{"time":"2020-02-17T11:39:27.658731Z"}`
+	if string(out) != want {
+		t.Errorf("Incorrect marshaled JSON: expected %#v, got %#v", want, string(out))
+	}
+}
@@ -0,0 +1,199 @@
+package iso8601
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IntervalSeparator is the string that separates the two components of an
+// ISO 8601 time interval ("<start>/<end>", "<start>/<duration>",
+// "<duration>/<end>") and the "R<n>/" prefix of a repeating interval. ISO
+// 8601 specifies "/" as the separator, but some profiles use "--" instead
+// to avoid colliding with URL or file path syntax; assign to this variable
+// before calling ParseISO8601Interval or ParseISO8601RepeatingInterval to
+// support those profiles.
+var IntervalSeparator = "/"
+
+func intervalParseError(s string) error {
+	return &time.ParseError{
+		Layout:     "ISO 8601 Interval",
+		Value:      s,
+		LayoutElem: "",
+		ValueElem:  "",
+		Message:    ": interval is not in ISO 8601 format",
+	}
+}
+
+// Interval represents an ISO 8601 time interval. It carries whichever of
+// StartTime, EndTime, and IntervalDuration were present in the parsed
+// text; Start, End, and Duration compute whichever side is missing from
+// the other two.
+type Interval struct {
+	StartTime        *time.Time
+	EndTime          *time.Time
+	IntervalDuration *Duration
+}
+
+// ParseISO8601Interval parses an ISO 8601 time interval in any of the four
+// forms the standard defines: "<start>/<end>", "<start>/<duration>",
+// "<duration>/<end>", or a bare "<duration>". The bare form leaves both
+// StartTime and EndTime nil, since the text alone does not anchor the
+// duration to a point in time; callers that need one should set
+// Interval.StartTime or Interval.EndTime themselves before calling Start
+// or End.
+func ParseISO8601Interval(s string) (Interval, error) {
+	parts := strings.SplitN(s, IntervalSeparator, 2)
+
+	if len(parts) == 1 {
+		d, err := ParseISO8601Duration(s)
+		if err != nil {
+			return Interval{}, intervalParseError(s)
+		}
+
+		return Interval{IntervalDuration: &d}, nil
+	}
+
+	left, right := parts[0], parts[1]
+
+	if d, err := ParseISO8601Duration(left); err == nil {
+		end, err := ParseISO8601Timestamp(right)
+		if err != nil {
+			return Interval{}, intervalParseError(s)
+		}
+
+		return Interval{EndTime: &end, IntervalDuration: &d}, nil
+	}
+
+	start, err := ParseISO8601Timestamp(left)
+	if err != nil {
+		return Interval{}, intervalParseError(s)
+	}
+
+	if d, err := ParseISO8601Duration(right); err == nil {
+		return Interval{StartTime: &start, IntervalDuration: &d}, nil
+	}
+
+	end, err := ParseISO8601Timestamp(right)
+	if err != nil {
+		return Interval{}, intervalParseError(s)
+	}
+
+	return Interval{StartTime: &start, EndTime: &end}, nil
+}
+
+// Start returns the beginning of the interval, computing it from EndTime
+// and IntervalDuration if StartTime was not given explicitly.
+func (iv Interval) Start() time.Time {
+	if iv.StartTime != nil {
+		return *iv.StartTime
+	}
+	if iv.EndTime != nil && iv.IntervalDuration != nil {
+		return subtractDuration(*iv.EndTime, *iv.IntervalDuration)
+	}
+	return time.Time{}
+}
+
+// End returns the end of the interval, computing it from StartTime and
+// IntervalDuration if EndTime was not given explicitly.
+func (iv Interval) End() time.Time {
+	if iv.EndTime != nil {
+		return *iv.EndTime
+	}
+	if iv.StartTime != nil && iv.IntervalDuration != nil {
+		return iv.IntervalDuration.AddTo(*iv.StartTime)
+	}
+	return time.Time{}
+}
+
+// Duration returns the length of the interval, computing it from
+// StartTime and EndTime if IntervalDuration was not given explicitly.
+func (iv Interval) Duration() Duration {
+	if iv.IntervalDuration != nil {
+		return *iv.IntervalDuration
+	}
+	if iv.StartTime != nil && iv.EndTime != nil {
+		return Duration{TimePart: iv.EndTime.Sub(*iv.StartTime)}
+	}
+	return Duration{}
+}
+
+// subtractDuration returns t with d subtracted from it; the inverse of
+// Duration.AddTo, undoing its steps in reverse order (the time-of-day
+// component first, then the calendar components).
+func subtractDuration(t time.Time, d Duration) time.Time {
+	return t.Add(-d.TimePart).AddDate(-d.Years, -d.Months, -(d.Weeks*7 + d.Days))
+}
+
+// RepeatingInterval iterates the occurrences of an ISO 8601 repeating
+// interval, e.g. "R5/2008-03-01T13:00:00Z/P1Y2M10DT2H30M".
+type RepeatingInterval struct {
+	interval  Interval
+	next      time.Time
+	duration  Duration
+	remaining int // -1 means unbounded
+}
+
+// ParseISO8601RepeatingInterval parses an ISO 8601 repeating interval: an
+// "R<n>/" or "R/" prefix (the former for n+1 occurrences, the latter for
+// an unbounded number of occurrences) followed by any of the forms
+// ParseISO8601Interval accepts.
+func ParseISO8601RepeatingInterval(s string) (*RepeatingInterval, error) {
+	if len(s) == 0 || s[0] != 'R' {
+		return nil, intervalParseError(s)
+	}
+
+	rest := s[1:]
+	sepIndex := strings.Index(rest, IntervalSeparator)
+	if sepIndex < 0 {
+		return nil, intervalParseError(s)
+	}
+
+	countStr, remainder := rest[:sepIndex], rest[sepIndex+len(IntervalSeparator):]
+
+	remaining := -1
+	if countStr != "" {
+		n, err := strconv.Atoi(countStr)
+		if err != nil || n < 0 {
+			return nil, intervalParseError(s)
+		}
+		remaining = n + 1
+	}
+
+	iv, err := ParseISO8601Interval(remainder)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RepeatingInterval{
+		interval:  iv,
+		next:      iv.Start(),
+		duration:  iv.Duration(),
+		remaining: remaining,
+	}, nil
+}
+
+// Next returns the start time of the next occurrence and true, or the
+// zero time and false once the repeating interval is exhausted. For an
+// unbounded repeating interval ("R/..."), Next always returns true; it is
+// up to the caller to stop iterating.
+func (r *RepeatingInterval) Next() (time.Time, bool) {
+	if r.remaining == 0 {
+		return time.Time{}, false
+	}
+
+	occurrence := r.next
+	r.next = r.duration.AddTo(r.next)
+
+	if r.remaining > 0 {
+		r.remaining--
+	}
+
+	return occurrence, true
+}
+
+// Remaining returns the number of occurrences left to produce, or -1 if
+// the repeating interval is unbounded.
+func (r *RepeatingInterval) Remaining() int {
+	return r.remaining
+}
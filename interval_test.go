@@ -0,0 +1,122 @@
+package iso8601
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalStartEnd(t *testing.T) {
+	iv, err := ParseISO8601Interval("2007-03-01T13:00:00Z/2008-05-11T15:30:00Z")
+	if err != nil {
+		t.Fatalf("Failed to parse interval: %#v\n", err)
+	}
+
+	wantStart := time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2008, 5, 11, 15, 30, 0, 0, time.UTC)
+
+	if !iv.Start().Equal(wantStart) {
+		t.Errorf("Incorrect start: expected %v, got %v", wantStart, iv.Start())
+	}
+	if !iv.End().Equal(wantEnd) {
+		t.Errorf("Incorrect end: expected %v, got %v", wantEnd, iv.End())
+	}
+}
+
+func TestIntervalStartDuration(t *testing.T) {
+	iv, err := ParseISO8601Interval("2007-03-01T13:00:00Z/P1Y2M10DT2H30M")
+	if err != nil {
+		t.Fatalf("Failed to parse interval: %#v\n", err)
+	}
+
+	wantEnd := time.Date(2008, 5, 11, 15, 30, 0, 0, time.UTC)
+	if !iv.End().Equal(wantEnd) {
+		t.Errorf("Incorrect end: expected %v, got %v", wantEnd, iv.End())
+	}
+}
+
+func TestIntervalDurationEnd(t *testing.T) {
+	iv, err := ParseISO8601Interval("P1Y2M10DT2H30M/2008-05-11T15:30:00Z")
+	if err != nil {
+		t.Fatalf("Failed to parse interval: %#v\n", err)
+	}
+
+	wantStart := time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC)
+	if !iv.Start().Equal(wantStart) {
+		t.Errorf("Incorrect start: expected %v, got %v", wantStart, iv.Start())
+	}
+}
+
+func TestIntervalBareDuration(t *testing.T) {
+	iv, err := ParseISO8601Interval("P1Y2M10DT2H30M")
+	if err != nil {
+		t.Fatalf("Failed to parse interval: %#v\n", err)
+	}
+
+	if iv.StartTime != nil || iv.EndTime != nil {
+		t.Errorf("Expected a bare duration to have no start or end: %#v", iv)
+	}
+}
+
+func TestIntervalCustomSeparator(t *testing.T) {
+	old := IntervalSeparator
+	IntervalSeparator = "--"
+	defer func() { IntervalSeparator = old }()
+
+	iv, err := ParseISO8601Interval("2007-03-01T13:00:00Z--2008-05-11T15:30:00Z")
+	if err != nil {
+		t.Fatalf("Failed to parse interval: %#v\n", err)
+	}
+
+	wantStart := time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC)
+	if !iv.Start().Equal(wantStart) {
+		t.Errorf("Incorrect start: expected %v, got %v", wantStart, iv.Start())
+	}
+}
+
+func TestRepeatingInterval(t *testing.T) {
+	ri, err := ParseISO8601RepeatingInterval("R2/2008-03-01T13:00:00Z/P1Y")
+	if err != nil {
+		t.Fatalf("Failed to parse repeating interval: %#v\n", err)
+	}
+
+	if remaining := ri.Remaining(); remaining != 3 {
+		t.Errorf("Expected 3 remaining occurrences, got %d", remaining)
+	}
+
+	want := []time.Time{
+		time.Date(2008, 3, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2009, 3, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 3, 1, 13, 0, 0, 0, time.UTC),
+	}
+
+	for i, w := range want {
+		occurrence, ok := ri.Next()
+		if !ok {
+			t.Fatalf("Expected occurrence %d, but iteration stopped early", i)
+		}
+		if !occurrence.Equal(w) {
+			t.Errorf("Incorrect occurrence %d: expected %v, got %v", i, w, occurrence)
+		}
+	}
+
+	if _, ok := ri.Next(); ok {
+		t.Errorf("Expected iteration to be exhausted")
+	}
+}
+
+func TestRepeatingIntervalUnbounded(t *testing.T) {
+	ri, err := ParseISO8601RepeatingInterval("R/2008-03-01T13:00:00Z/P1D")
+	if err != nil {
+		t.Fatalf("Failed to parse repeating interval: %#v\n", err)
+	}
+
+	if remaining := ri.Remaining(); remaining != -1 {
+		t.Errorf("Expected -1 (unbounded) remaining occurrences, got %d", remaining)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, ok := ri.Next(); !ok {
+			t.Errorf("Expected an unbounded interval to keep producing occurrences")
+		}
+	}
+}
@@ -2,8 +2,8 @@ package iso8601
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -12,15 +12,25 @@ import (
 // is in UTC, and returns the time zone as 'Z'.
 const ISO8601CompactFormat = "20060102T150405Z"
 
-var iso8601Variants [6]*regexp.Regexp
+// zoneCache holds *time.Location values already built by scanZone, keyed by
+// their offset in seconds east of UTC, so that repeated timestamps using
+// the same offset don't each allocate a fresh time.FixedZone.
+var zoneCache sync.Map
+
+// dateFromISOWeek converts an ISO 8601 week date into the Gregorian date it
+// denotes. week is the ISO week number (1-53); weekday is 1 for Monday
+// through 7 for Sunday. Week 01 of a year is defined as the week containing
+// that year's first Thursday.
+func dateFromISOWeek(year, week, weekday int) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+
+	jan4Weekday := int(jan4.Weekday())
+	if jan4Weekday == 0 {
+		jan4Weekday = 7
+	}
 
-func init() {
-	iso8601Variants[0] = regexp.MustCompile(`^([0-9]{4})-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])[Tt ]([01][0-9]|2[0-3]):([0-5][0-9]):([0-5][0-9]|6[01])(?:[.,]([0-9]{1,9}))?(Z|[-+][01][0-9]:?(?:[0-5][0-9])?)$`)
-	iso8601Variants[1] = regexp.MustCompile(`^([0-9]{4})(0[1-9]|1[0-2])(0[1-9]|[12][0-9]|3[01])[Tt ]([01][0-9]|2[0-3])([0-5][0-9])([0-5][0-9]|6[01])(?:[.,]([0-9]{1,9}))?(Z|[-+][01][0-9]:?(?:[0-5][0-9])?)$`)
-	iso8601Variants[2] = regexp.MustCompile(`^([0-9]{4})-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])[Tt ]([01][0-9]|2[0-3])([0-5][0-9])([0-5][0-9]|6[01])(?:[.,]([0-9]{1,9}))?(Z|[-+][01][0-9]:?(?:[0-5][0-9])?)$`)
-	iso8601Variants[3] = regexp.MustCompile(`^([0-9]{4})(0[1-9]|1[0-2])(0[1-9]|[12][0-9]|3[01])[Tt ]([01][0-9]|2[0-3]):([0-5][0-9]):([0-5][0-9]|6[01])(?:[.,]([0-9]{1,9}))?(Z|[-+][01][0-9]:?(?:[0-5][0-9])?)$`)
-	iso8601Variants[4] = regexp.MustCompile(`^([0-9]{4})-(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$`)
-	iso8601Variants[5] = regexp.MustCompile(`^([0-9]{4})(0[1-9]|1[0-2])(0[1-9]|[12][0-9]|3[01])$`)
+	week1Monday := jan4.AddDate(0, 0, -(jan4Weekday - 1))
+	return week1Monday.AddDate(0, 0, (week-1)*7+(weekday-1))
 }
 
 func atoip(s string) int {
@@ -31,76 +41,360 @@ func atoip(s string) int {
 	}
 }
 
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// digitRunLength returns the number of consecutive ASCII digits at the
+// start of s.
+func digitRunLength(s string) int {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return n
+}
+
+// parseUint parses s, which must consist entirely of decimal digits, as an
+// unsigned integer and checks that it falls within [min, max].
+func parseUint(s string, min, max int) (x int, ok bool) {
+	if s == "" {
+		return 0, false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isDigit(c) {
+			return 0, false
+		}
+		x = x*10 + int(c-'0')
+	}
+
+	if x < min || x > max {
+		return 0, false
+	}
+
+	return x, true
+}
+
+// MinExpandedYearDigits is the minimum number of digits ParseISO8601Timestamp
+// requires in the year of ISO 8601's "expanded representation"
+// (+YYYYYY/-YYYYYY), used for years outside the usual 4-digit range. It
+// also fixes the year width assumed for a signed year in basic
+// (unseparated) form, since nothing there delimits where the year's
+// digits end and the month/day's (or day-of-year's) begin -- per the
+// standard, that width must be agreed on out of band. The default, 4,
+// accepts any signed year of 4 or more digits in extended form (where the
+// '-' before the month unambiguously ends the year) but leaves basic-form
+// expanded years disabled, since a width of 4 is just the ordinary form.
+var MinExpandedYearDigits = 4
+
+// scanDate consumes a Gregorian calendar date ("2006-01-02"/"20060102"),
+// ordinal date ("2006-051"/"2006051"), or week date
+// ("2006-W07-1"/"2006W071") from the start of s, in either extended or
+// basic form, optionally prefixed with a '+' or '-' sign and an expanded
+// (more than 4 digit) year. It returns the Gregorian year/month/day the
+// date denotes and the number of bytes of s consumed.
+func scanDate(s string) (year, month, day, pos int, ok bool) {
+	i := 0
+	negative := false
+
+	signed := len(s) > 0 && (s[0] == '+' || s[0] == '-')
+	if signed {
+		negative = s[0] == '-'
+		i = 1
+	}
+
+	yearWidth := 4
+	extended := false
+
+	if signed {
+		run := digitRunLength(s[i:])
+		if run >= 4 && i+run < len(s) && (s[i+run] == '-' || s[i+run] == 'W') {
+			// The year is unambiguously delimited by the '-' before the
+			// month/day-of-year, or by the 'W' of a week date.
+			if run < MinExpandedYearDigits {
+				return 0, 0, 0, 0, false
+			}
+			yearWidth = run
+			extended = s[i+run] == '-'
+		} else {
+			// No delimiter marks the end of the year: fall back to the
+			// pre-agreed width.
+			yearWidth = MinExpandedYearDigits
+		}
+	} else if i+4 < len(s) {
+		extended = s[i+4] == '-'
+	}
+
+	if i+yearWidth > len(s) {
+		return 0, 0, 0, 0, false
+	}
+
+	year, ok = parseUint(s[i:i+yearWidth], 0, 1<<31-1)
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	if negative {
+		year = -year
+	}
+	i += yearWidth
+
+	if extended {
+		if i >= len(s) || s[i] != '-' {
+			return 0, 0, 0, 0, false
+		}
+		i++
+	}
+
+	if i < len(s) && s[i] == 'W' {
+		i++
+
+		if i+2 > len(s) {
+			return 0, 0, 0, 0, false
+		}
+		week, ok := parseUint(s[i:i+2], 1, 53)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+		i += 2
+
+		if extended {
+			if i >= len(s) || s[i] != '-' {
+				return 0, 0, 0, 0, false
+			}
+			i++
+		}
+
+		if i+1 > len(s) {
+			return 0, 0, 0, 0, false
+		}
+		weekday, ok := parseUint(s[i:i+1], 1, 7)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+		i++
+
+		date := dateFromISOWeek(year, week, weekday)
+		return date.Year(), int(date.Month()), date.Day(), i, true
+	}
+
+	runLen := digitRunLength(s[i:])
+
+	switch {
+	case runLen == 3:
+		doy, ok := parseUint(s[i:i+3], 1, 366)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+		i += 3
+
+		date := time.Date(year, time.January, doy, 0, 0, 0, 0, time.UTC)
+		return date.Year(), int(date.Month()), date.Day(), i, true
+
+	case extended && runLen == 2:
+		month, ok := parseUint(s[i:i+2], 1, 12)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+		i += 2
+
+		if i >= len(s) || s[i] != '-' {
+			return 0, 0, 0, 0, false
+		}
+		i++
+
+		if i+2 > len(s) {
+			return 0, 0, 0, 0, false
+		}
+		day, ok := parseUint(s[i:i+2], 1, 31)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+		i += 2
+
+		return year, month, day, i, true
+
+	case !extended && runLen == 4:
+		month, ok := parseUint(s[i:i+2], 1, 12)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+
+		day, ok := parseUint(s[i+2:i+4], 1, 31)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+		i += 4
+
+		return year, month, day, i, true
+
+	default:
+		return 0, 0, 0, 0, false
+	}
+}
+
+// scanTimeOfDay consumes a time-of-day ("15:04:05"/"150405"), with an
+// optional fractional-seconds component introduced by '.' or ',', starting
+// at s[i]. It returns the parsed fields and the number of bytes of s
+// consumed, counting from the start of s (not from i).
+func scanTimeOfDay(s string, i int) (hour, minute, second, nanosec, pos int, ok bool) {
+	if i+2 > len(s) {
+		return 0, 0, 0, 0, 0, false
+	}
+	hour, ok = parseUint(s[i:i+2], 0, 23)
+	if !ok {
+		return 0, 0, 0, 0, 0, false
+	}
+	i += 2
+
+	extended := i < len(s) && s[i] == ':'
+	if extended {
+		i++
+	}
+
+	if i+2 > len(s) {
+		return 0, 0, 0, 0, 0, false
+	}
+	minute, ok = parseUint(s[i:i+2], 0, 59)
+	if !ok {
+		return 0, 0, 0, 0, 0, false
+	}
+	i += 2
+
+	if extended {
+		if i >= len(s) || s[i] != ':' {
+			return 0, 0, 0, 0, 0, false
+		}
+		i++
+	}
+
+	if i+2 > len(s) {
+		return 0, 0, 0, 0, 0, false
+	}
+	// 60 and 61 are accepted to allow for (repeated) leap seconds.
+	second, ok = parseUint(s[i:i+2], 0, 61)
+	if !ok {
+		return 0, 0, 0, 0, 0, false
+	}
+	i += 2
+
+	if i < len(s) && (s[i] == '.' || s[i] == ',') {
+		i++
+		start := i
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+
+		fracLen := i - start
+		if fracLen == 0 || fracLen > 9 {
+			return 0, 0, 0, 0, 0, false
+		}
+
+		nanosec, ok = parseUint(s[start:i], 0, 999999999)
+		if !ok {
+			return 0, 0, 0, 0, 0, false
+		}
+
+		// Fractional seconds don't need to be nanosecond resolution: scale
+		// up arithmetically rather than padding the digit string with
+		// zeros, which would allocate on every call.
+		for n := fracLen; n < 9; n++ {
+			nanosec *= 10
+		}
+	}
+
+	return hour, minute, second, nanosec, i, true
+}
+
+// scanZone parses the remainder of a timestamp as an ISO 8601 time zone:
+// either "Z" or a signed offset, "+HH:MM", "+HHMM", or "+HH".
+func scanZone(rest string) (*time.Location, bool) {
+	if rest == "Z" {
+		return time.UTC, true
+	}
+
+	if len(rest) < 3 || (rest[0] != '+' && rest[0] != '-') {
+		return nil, false
+	}
+
+	sign := rest[0]
+	tzHour, ok := parseUint(rest[1:3], 0, 19)
+	if !ok {
+		return nil, false
+	}
+
+	i := 3
+	tzMin := 0
+
+	if i < len(rest) {
+		if rest[i] == ':' {
+			i++
+		}
+
+		if i+2 != len(rest) {
+			return nil, false
+		}
+
+		tzMin, ok = parseUint(rest[i:i+2], 0, 59)
+		if !ok {
+			return nil, false
+		}
+		i += 2
+	}
+
+	if i != len(rest) {
+		return nil, false
+	}
+
+	offsetSeconds := tzHour*3600 + tzMin*60
+	if sign == '-' {
+		offsetSeconds = -offsetSeconds
+	}
+
+	if loc, ok := zoneCache.Load(offsetSeconds); ok {
+		return loc.(*time.Location), true
+	}
+
+	loc := time.FixedZone(fmt.Sprintf("%c%02d:%02d", sign, tzHour, tzMin), offsetSeconds)
+	actual, _ := zoneCache.LoadOrStore(offsetSeconds, loc)
+	return actual.(*time.Location), true
+}
+
 // ParseISO8601Timestamp converts an ISO 8601 timestamp into a time.Time
 // result. Compared to time.Parse(time.RFC3339) and
 // time.Parse(time.RFC3339Nano), this accepts the full range of ISO 8601
 // formats and the RFC 3339 variants.
 func ParseISO8601Timestamp(s string) (time.Time, error) {
-	for _, re := range iso8601Variants {
-		if re.MatchString(s) {
-			var year, day, hour, minute, second, nanosecs int
-			var month time.Month
-			var fracSecStr, tzStr string
-
-			parts := re.FindStringSubmatch(s)
-			year = atoip(parts[1])
-			month = time.Month(atoip(parts[2]))
-			day = atoip(parts[3])
-			nanosecs = 0
-
-			if len(parts) > 4 {
-				hour = atoip(parts[4])
-				minute = atoip(parts[5])
-				second = atoip(parts[6])
-				fracSecStr = parts[7]
-
-				// fractional seconds don't need to be nanosecond resolution.
-				// Pad the right with zeros to make it so.
-				if fracSecStr != "" {
-					for len(fracSecStr) < 9 {
-						fracSecStr = fracSecStr + "0"
-					}
-
-					nanosecs = atoip(fracSecStr)
-				}
-			}
+	year, month, day, i, ok := scanDate(s)
+	if !ok {
+		return time.Time{}, isoParseError(s)
+	}
 
-			var loc *time.Location
+	if i == len(s) {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), nil
+	}
 
-			if len(parts) > 4 {
-				tzStr = parts[8]
-			} else {
-				tzStr = "Z"
-			}
+	if s[i] != 'T' && s[i] != 't' && s[i] != ' ' {
+		return time.Time{}, isoParseError(s)
+	}
+	i++
 
-			if tzStr == "Z" {
-				loc = time.UTC
-			} else {
-				signStr := tzStr[0]
-				sign := 1
-				if signStr == '-' {
-					sign = -1
-				}
-
-				tzHour := atoip(tzStr[1:3])
-				var tzMin int
-
-				if tzStr[3] == ':' {
-					tzMin = atoip(tzStr[4:6])
-				} else {
-					tzMin = atoip(tzStr[3:5])
-				}
-
-				loc = time.FixedZone(
-					fmt.Sprintf("%c%02d:%02d", signStr, tzHour, tzMin),
-					sign*(tzHour*3600+tzMin*60))
-			}
+	hour, minute, second, nanosec, i, ok := scanTimeOfDay(s, i)
+	if !ok {
+		return time.Time{}, isoParseError(s)
+	}
 
-			return time.Date(year, month, day, hour, minute, second, nanosecs, loc), nil
-		}
+	loc, ok := scanZone(s[i:])
+	if !ok {
+		return time.Time{}, isoParseError(s)
 	}
 
-	return time.Time{}, &time.ParseError{
+	return time.Date(year, time.Month(month), day, hour, minute, second, nanosec, loc), nil
+}
+
+func isoParseError(s string) error {
+	return &time.ParseError{
 		Layout:     "ISO 8601",
 		Value:      s,
 		LayoutElem: "",
@@ -195,7 +489,7 @@ func (t Time) Round(d time.Duration) Time {
 // String returns the time formatted using the RFC3339Nano string:
 //	"2006-01-02T15:04:05.999999999Z07:00"
 func (t Time) String() string {
-	return t.Format(time.RFC3339Nano)
+	return t.Time.Format(time.RFC3339Nano)
 }
 
 // Truncate returns the result of rounding t down to a multiple of d (since the zero time).
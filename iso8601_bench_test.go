@@ -0,0 +1,50 @@
+package iso8601
+
+import (
+	"testing"
+	"time"
+)
+
+func BenchmarkParseISO8601TimestampExtended(b *testing.B) {
+	const s = "2020-02-17T11:39:27.658731+00:00"
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseISO8601Timestamp(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseISO8601TimestampBasic(b *testing.B) {
+	const s = "20200217T113927.658731+0000"
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseISO8601Timestamp(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseISO8601TimestampFraction(b *testing.B) {
+	// A fraction shorter than nanosecond resolution (the common case)
+	// exercises scanTimeOfDay's padding path, which must scale the parsed
+	// digits arithmetically rather than allocate a padded string.
+	const s = "2020-02-17T11:39:27.658+00:00"
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseISO8601Timestamp(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseRFC3339Nano(b *testing.B) {
+	const s = "2020-02-17T11:39:27.658731+00:00"
+
+	for i := 0; i < b.N; i++ {
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
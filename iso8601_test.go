@@ -80,6 +80,84 @@ func TestValidDates(t *testing.T) {
 
 }
 
+func TestOrdinalDates(t *testing.T) {
+	for _, s := range []string{
+		"2020-051", "2020051", "2020-051T11:39:27Z", "2020051T113927Z",
+		"2020-051T11:39:27+00:00", "2020-051t11:39:27Z", "2020-051 11:39:27Z"} {
+		if ts, err := ParseISO8601Timestamp(s); err != nil {
+			t.Errorf("Failed to parse timestamp: %#v %#v\n", s, err)
+		} else if ts.Year() != 2020 || ts.Month() != 2 || ts.Day() != 20 {
+			t.Errorf("Incorrect timestamp value for %#v: expected 2020-02-20, got %v", s, ts)
+		}
+	}
+
+	// Day 366 in a leap year
+	if ts, err := ParseISO8601Timestamp("2020-366"); err != nil {
+		t.Errorf("Failed to parse timestamp: %#v\n", err)
+	} else if ts.Year() != 2020 || ts.Month() != 12 || ts.Day() != 31 {
+		t.Errorf("Incorrect timestamp value for 2020-366: expected 2020-12-31, got %v", ts)
+	}
+}
+
+func TestWeekDates(t *testing.T) {
+	for _, s := range []string{
+		"2020-W07-1", "2020W071", "2020-W07-1T11:39:27Z", "2020W071T113927Z",
+		"2020-W07-1T11:39:27+00:00"} {
+		if ts, err := ParseISO8601Timestamp(s); err != nil {
+			t.Errorf("Failed to parse timestamp: %#v %#v\n", s, err)
+		} else if ts.Year() != 2020 || ts.Month() != 2 || ts.Day() != 10 {
+			t.Errorf("Incorrect timestamp value for %#v: expected 2020-02-10, got %v", s, ts)
+		}
+	}
+
+	// 2015-W01-1 falls in the preceding Gregorian year's December, since
+	// 2015-01-01 is a Thursday and ISO week 01 always starts on a Monday.
+	if ts, err := ParseISO8601Timestamp("2015-W01-1"); err != nil {
+		t.Errorf("Failed to parse timestamp: %#v\n", err)
+	} else if ts.Year() != 2014 || ts.Month() != 12 || ts.Day() != 29 {
+		t.Errorf("Incorrect timestamp value for 2015-W01-1: expected 2014-12-29, got %v", ts)
+	}
+}
+
+func TestExpandedYears(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		year int
+	}{
+		{"+10000-01-01", 10000},
+		{"-0001-01-01", -1},
+		{"+0001-01-01", 1},
+	} {
+		if ts, err := ParseISO8601Timestamp(tc.s); err != nil {
+			t.Errorf("Failed to parse timestamp: %#v %#v\n", tc.s, err)
+		} else if ts.Year() != tc.year || ts.Month() != 1 || ts.Day() != 1 {
+			t.Errorf("Incorrect timestamp value for %#v: expected year %d, got %v", tc.s, tc.year, ts)
+		}
+	}
+
+	// An unsigned wide year is ambiguous and must be rejected.
+	if _, err := ParseISO8601Timestamp("10000-01-01"); err == nil {
+		t.Errorf("Expected an error on unsigned expanded year\n")
+	}
+
+	// In basic form, a signed year's width must be agreed on out of band
+	// via MinExpandedYearDigits; it defaults to 4, so a 6-digit basic-form
+	// year is rejected until the caller opts in.
+	if _, err := ParseISO8601Timestamp("+1000000101"); err == nil {
+		t.Errorf("Expected an error on an unagreed-width expanded basic-form year\n")
+	}
+
+	old := MinExpandedYearDigits
+	MinExpandedYearDigits = 6
+	defer func() { MinExpandedYearDigits = old }()
+
+	if ts, err := ParseISO8601Timestamp("+1000000101"); err != nil {
+		t.Errorf("Failed to parse timestamp with MinExpandedYearDigits=6: %#v\n", err)
+	} else if ts.Year() != 100000 || ts.Month() != 1 || ts.Day() != 1 {
+		t.Errorf("Incorrect timestamp value for %#v: expected 100000-01-01, got %v", "+1000000101", ts)
+	}
+}
+
 func TestInvalidDates(t *testing.T) {
 	for _, s := range []string{"1900-1231T00:10:20Z", "1900-12-31T00:1020Z"} {
 		if _, err := ParseISO8601Timestamp(s); err == nil {